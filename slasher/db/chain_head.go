@@ -0,0 +1,35 @@
+package db
+
+import (
+	"context"
+
+	"github.com/gogo/protobuf/proto"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	bolt "go.etcd.io/bbolt"
+)
+
+// ChainHead returns the most recently persisted chain head, or nil if none
+// has been saved yet.
+func (s *Store) ChainHead(ctx context.Context) (*ethpb.ChainHead, error) {
+	var head *ethpb.ChainHead
+	err := s.db.View(func(tx *bolt.Tx) error {
+		enc := tx.Bucket(chainHeadBucket).Get(chainHeadKey)
+		if enc == nil {
+			return nil
+		}
+		head = &ethpb.ChainHead{}
+		return proto.Unmarshal(enc, head)
+	})
+	return head, err
+}
+
+// SaveChainHead persists head as the latest detected chain head.
+func (s *Store) SaveChainHead(ctx context.Context, head *ethpb.ChainHead) error {
+	enc, err := proto.Marshal(head)
+	if err != nil {
+		return err
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(chainHeadBucket).Put(chainHeadKey, enc)
+	})
+}
@@ -0,0 +1,55 @@
+// Package db defines the slasher's persistent storage interface and a
+// bolt-backed implementation of it.
+package db
+
+import (
+	"context"
+
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+)
+
+// Database defines the persistent storage the slasher's detection pipeline
+// relies on: the latest detected chain head, a durable record of which
+// slashings have already been dispatched to downstream sinks, and a
+// persistent outbox of slashings whose delivery has not yet been confirmed.
+type Database interface {
+	// ChainHead returns the most recently persisted chain head, or nil if
+	// none has been saved yet.
+	ChainHead(ctx context.Context) (*ethpb.ChainHead, error)
+	// SaveChainHead persists head as the latest detected chain head.
+	SaveChainHead(ctx context.Context, head *ethpb.ChainHead) error
+
+	// SaveDetectedAttesterSlashingKey records that a slashing implicating
+	// validatorIndex for the given source/target epoch pair has been
+	// dispatched. It reports whether the key was newly recorded (false if
+	// already present from a prior call).
+	SaveDetectedAttesterSlashingKey(ctx context.Context, validatorIndex, sourceEpoch, targetEpoch uint64) (bool, error)
+	// SaveDetectedProposerSlashingKey records that a slashing implicating
+	// proposerIndex at slot has been dispatched. It reports whether the key
+	// was newly recorded (false if already present from a prior call).
+	SaveDetectedProposerSlashingKey(ctx context.Context, proposerIndex, slot uint64) (bool, error)
+
+	// SaveAttesterSlashingOutboxEntry persists slash to the outbox ahead of
+	// delivery. It reports whether the entry is newly recorded (false if an
+	// identical entry is already pending; an entry deleted after delivery is
+	// not remembered).
+	SaveAttesterSlashingOutboxEntry(ctx context.Context, slash *ethpb.AttesterSlashing) (bool, error)
+	// DeleteAttesterSlashingOutboxEntry removes slash from the outbox once
+	// its delivery has been confirmed.
+	DeleteAttesterSlashingOutboxEntry(ctx context.Context, slash *ethpb.AttesterSlashing) error
+	// PendingAttesterSlashingOutbox returns every attester slashing outbox
+	// entry that has not yet been deleted.
+	PendingAttesterSlashingOutbox(ctx context.Context) ([]*ethpb.AttesterSlashing, error)
+
+	// SaveProposerSlashingOutboxEntry persists slash to the outbox ahead of
+	// delivery. It reports whether the entry is newly recorded (false if an
+	// identical entry is already pending; an entry deleted after delivery is
+	// not remembered).
+	SaveProposerSlashingOutboxEntry(ctx context.Context, slash *ethpb.ProposerSlashing) (bool, error)
+	// DeleteProposerSlashingOutboxEntry removes slash from the outbox once
+	// its delivery has been confirmed.
+	DeleteProposerSlashingOutboxEntry(ctx context.Context, slash *ethpb.ProposerSlashing) error
+	// PendingProposerSlashingOutbox returns every proposer slashing outbox
+	// entry that has not yet been deleted.
+	PendingProposerSlashingOutbox(ctx context.Context) ([]*ethpb.ProposerSlashing, error)
+}
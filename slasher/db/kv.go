@@ -0,0 +1,44 @@
+package db
+
+import (
+	bolt "go.etcd.io/bbolt"
+)
+
+// Store is a bolt-backed implementation of Database.
+type Store struct {
+	db *bolt.DB
+}
+
+var _ Database = (*Store)(nil)
+
+// NewKVStore opens (creating if necessary) a bolt database at path and
+// returns a Store backed by it, with every bucket Database relies on
+// created up front.
+func NewKVStore(path string) (*Store, error) {
+	boltDB, err := bolt.Open(path, 0600, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := boltDB.Update(func(tx *bolt.Tx) error {
+		for _, bucket := range [][]byte{
+			chainHeadBucket,
+			detectedAttesterSlashingKeysBucket,
+			detectedProposerSlashingKeysBucket,
+			attesterSlashingOutboxBucket,
+			proposerSlashingOutboxBucket,
+		} {
+			if _, err := tx.CreateBucketIfNotExists(bucket); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return &Store{db: boltDB}, nil
+}
+
+// Close closes the underlying bolt database.
+func (s *Store) Close() error {
+	return s.db.Close()
+}
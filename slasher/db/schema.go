@@ -0,0 +1,15 @@
+package db
+
+// Bucket names for the slasher's bolt-backed store. Each bucket is created
+// once, in NewKVStore, and never deleted.
+var (
+	chainHeadBucket                    = []byte("chain-head")
+	detectedAttesterSlashingKeysBucket = []byte("detected-attester-slashing-keys")
+	detectedProposerSlashingKeysBucket = []byte("detected-proposer-slashing-keys")
+	attesterSlashingOutboxBucket       = []byte("attester-slashing-outbox")
+	proposerSlashingOutboxBucket       = []byte("proposer-slashing-outbox")
+)
+
+// chainHeadKey is the sole key written to chainHeadBucket: the store only
+// ever tracks the single, latest chain head.
+var chainHeadKey = []byte("latest")
@@ -0,0 +1,65 @@
+package db
+
+import (
+	"context"
+	"encoding/binary"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// attesterSlashingDedupKey encodes the (validator index, source epoch,
+// target epoch) triple identifying a single validator's slashable offense,
+// independent of which two attestations were used to detect it.
+func attesterSlashingDedupKey(validatorIndex, sourceEpoch, targetEpoch uint64) []byte {
+	key := make([]byte, 24)
+	binary.BigEndian.PutUint64(key[0:8], validatorIndex)
+	binary.BigEndian.PutUint64(key[8:16], sourceEpoch)
+	binary.BigEndian.PutUint64(key[16:24], targetEpoch)
+	return key
+}
+
+// proposerSlashingDedupKey encodes the (proposer index, slot) pair
+// identifying a single validator's slashable double proposal, independent of
+// which two block headers were used to detect it.
+func proposerSlashingDedupKey(proposerIndex, slot uint64) []byte {
+	key := make([]byte, 16)
+	binary.BigEndian.PutUint64(key[0:8], proposerIndex)
+	binary.BigEndian.PutUint64(key[8:16], slot)
+	return key
+}
+
+// SaveDetectedAttesterSlashingKey records that a slashing implicating
+// validatorIndex for the given source/target epoch pair has been dispatched.
+// It reports whether the key was newly recorded (false if already present
+// from a prior call, including one from a process that has since restarted).
+func (s *Store) SaveDetectedAttesterSlashingKey(ctx context.Context, validatorIndex, sourceEpoch, targetEpoch uint64) (bool, error) {
+	key := attesterSlashingDedupKey(validatorIndex, sourceEpoch, targetEpoch)
+	isNew := false
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		bkt := tx.Bucket(detectedAttesterSlashingKeysBucket)
+		if bkt.Get(key) != nil {
+			return nil
+		}
+		isNew = true
+		return bkt.Put(key, []byte{1})
+	})
+	return isNew, err
+}
+
+// SaveDetectedProposerSlashingKey records that a slashing implicating
+// proposerIndex at slot has been dispatched. It reports whether the key was
+// newly recorded (false if already present from a prior call, including one
+// from a process that has since restarted).
+func (s *Store) SaveDetectedProposerSlashingKey(ctx context.Context, proposerIndex, slot uint64) (bool, error) {
+	key := proposerSlashingDedupKey(proposerIndex, slot)
+	isNew := false
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		bkt := tx.Bucket(detectedProposerSlashingKeysBucket)
+		if bkt.Get(key) != nil {
+			return nil
+		}
+		isNew = true
+		return bkt.Put(key, []byte{1})
+	})
+	return isNew, err
+}
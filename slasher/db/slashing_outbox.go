@@ -0,0 +1,110 @@
+package db
+
+import (
+	"context"
+
+	"github.com/gogo/protobuf/proto"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	bolt "go.etcd.io/bbolt"
+)
+
+// SaveAttesterSlashingOutboxEntry persists slash to the outbox ahead of
+// delivery, keyed by its own encoding so an identical slashing submitted
+// twice (e.g. re-detected after a historical-detection restart) is
+// recognized as already pending rather than stored twice. It reports
+// whether the entry is newly recorded.
+func (s *Store) SaveAttesterSlashingOutboxEntry(ctx context.Context, slash *ethpb.AttesterSlashing) (bool, error) {
+	enc, err := proto.Marshal(slash)
+	if err != nil {
+		return false, err
+	}
+	isNew := false
+	err = s.db.Update(func(tx *bolt.Tx) error {
+		bkt := tx.Bucket(attesterSlashingOutboxBucket)
+		if bkt.Get(enc) != nil {
+			return nil
+		}
+		isNew = true
+		return bkt.Put(enc, enc)
+	})
+	return isNew, err
+}
+
+// DeleteAttesterSlashingOutboxEntry removes slash from the outbox once its
+// delivery has been confirmed.
+func (s *Store) DeleteAttesterSlashingOutboxEntry(ctx context.Context, slash *ethpb.AttesterSlashing) error {
+	enc, err := proto.Marshal(slash)
+	if err != nil {
+		return err
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(attesterSlashingOutboxBucket).Delete(enc)
+	})
+}
+
+// PendingAttesterSlashingOutbox returns every attester slashing outbox entry
+// that has not yet been deleted.
+func (s *Store) PendingAttesterSlashingOutbox(ctx context.Context) ([]*ethpb.AttesterSlashing, error) {
+	var pending []*ethpb.AttesterSlashing
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(attesterSlashingOutboxBucket).ForEach(func(_, enc []byte) error {
+			slash := &ethpb.AttesterSlashing{}
+			if err := proto.Unmarshal(enc, slash); err != nil {
+				return err
+			}
+			pending = append(pending, slash)
+			return nil
+		})
+	})
+	return pending, err
+}
+
+// SaveProposerSlashingOutboxEntry persists slash to the outbox ahead of
+// delivery, keyed by its own encoding so an identical slashing submitted
+// twice is recognized as already pending rather than stored twice. It
+// reports whether the entry is newly recorded.
+func (s *Store) SaveProposerSlashingOutboxEntry(ctx context.Context, slash *ethpb.ProposerSlashing) (bool, error) {
+	enc, err := proto.Marshal(slash)
+	if err != nil {
+		return false, err
+	}
+	isNew := false
+	err = s.db.Update(func(tx *bolt.Tx) error {
+		bkt := tx.Bucket(proposerSlashingOutboxBucket)
+		if bkt.Get(enc) != nil {
+			return nil
+		}
+		isNew = true
+		return bkt.Put(enc, enc)
+	})
+	return isNew, err
+}
+
+// DeleteProposerSlashingOutboxEntry removes slash from the outbox once its
+// delivery has been confirmed.
+func (s *Store) DeleteProposerSlashingOutboxEntry(ctx context.Context, slash *ethpb.ProposerSlashing) error {
+	enc, err := proto.Marshal(slash)
+	if err != nil {
+		return err
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(proposerSlashingOutboxBucket).Delete(enc)
+	})
+}
+
+// PendingProposerSlashingOutbox returns every proposer slashing outbox entry
+// that has not yet been deleted.
+func (s *Store) PendingProposerSlashingOutbox(ctx context.Context) ([]*ethpb.ProposerSlashing, error) {
+	var pending []*ethpb.ProposerSlashing
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(proposerSlashingOutboxBucket).ForEach(func(_, enc []byte) error {
+			slash := &ethpb.ProposerSlashing{}
+			if err := proto.Unmarshal(enc, slash); err != nil {
+				return err
+			}
+			pending = append(pending, slash)
+			return nil
+		})
+	})
+	return pending, err
+}
@@ -0,0 +1,64 @@
+package detection
+
+import (
+	"context"
+
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/slasher/db"
+)
+
+// slashingDeduper gates slashing delivery on a persistent, slasherDB-backed
+// record of which (validator index, source epoch, target epoch) and
+// (proposer index, slot) keys have already been dispatched. Backing this by
+// slasherDB rather than an in-process map is required because the most
+// common source of repeated detection, batched historical-detection
+// checkpointing, reprocesses up to BatchSize already-detected epochs on
+// every restart: an in-memory-only deduper would be empty again by the time
+// that replay happens and would wave every slashing in the reprocessed
+// batch back through as "new".
+type slashingDeduper struct {
+	slasherDB db.Database
+}
+
+// newSlashingDeduper returns a slashingDeduper backed by slasherDB.
+func newSlashingDeduper(slasherDB db.Database) *slashingDeduper {
+	return &slashingDeduper{slasherDB: slasherDB}
+}
+
+// isDuplicateAttesterSlashing reports whether every validator index in
+// slashableIndices has already been dispatched for the same source/target
+// epoch pair as slash. Indices seen for the first time are persisted so a
+// later, identical slashing, even one detected after a restart, is
+// recognized as a duplicate.
+func (d *slashingDeduper) isDuplicateAttesterSlashing(ctx context.Context, slash *ethpb.AttesterSlashing, slashableIndices []uint64) bool {
+	sourceEpoch := slash.Attestation_1.Data.Source.Epoch
+	targetEpoch := slash.Attestation_1.Data.Target.Epoch
+	allSeen := len(slashableIndices) > 0
+	for _, idx := range slashableIndices {
+		isNew, err := d.slasherDB.SaveDetectedAttesterSlashingKey(ctx, idx, sourceEpoch, targetEpoch)
+		if err != nil {
+			log.WithError(err).Error("Could not persist attester slashing dedup key, treating as not a duplicate")
+			allSeen = false
+			continue
+		}
+		if isNew {
+			allSeen = false
+		}
+	}
+	return allSeen
+}
+
+// isDuplicateProposerSlashing reports whether slash's proposer index and
+// slot have already been dispatched, persisting the key if not.
+func (d *slashingDeduper) isDuplicateProposerSlashing(ctx context.Context, slash *ethpb.ProposerSlashing) bool {
+	isNew, err := d.slasherDB.SaveDetectedProposerSlashingKey(
+		ctx,
+		slash.Header_1.Header.ProposerIndex,
+		slash.Header_1.Header.Slot,
+	)
+	if err != nil {
+		log.WithError(err).Error("Could not persist proposer slashing dedup key, treating as not a duplicate")
+		return false
+	}
+	return !isNew
+}
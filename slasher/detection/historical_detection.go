@@ -0,0 +1,217 @@
+package detection
+
+import (
+	"context"
+	"sync"
+
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"go.opencensus.io/trace"
+)
+
+// HistoricalDetectionConfig controls the concurrency and checkpointing
+// behavior of the historical chain data detection pipeline.
+type HistoricalDetectionConfig struct {
+	// Workers is the number of goroutines concurrently fetching historical
+	// attestations for distinct epochs from the beacon node.
+	Workers uint64
+	// PrefetchDepth bounds how many epochs can be in flight (fetched or
+	// fetching) ahead of the epoch currently awaiting detection.
+	PrefetchDepth uint64
+	// BatchSize is the number of contiguous, processed epochs grouped
+	// together before the chain head checkpoint is persisted to disk.
+	BatchSize uint64
+}
+
+// DefaultHistoricalDetectionConfig returns sane defaults for historical
+// detection concurrency when none are provided by the caller.
+func DefaultHistoricalDetectionConfig() *HistoricalDetectionConfig {
+	return &HistoricalDetectionConfig{
+		Workers:       4,
+		PrefetchDepth: 16,
+		BatchSize:     8,
+	}
+}
+
+// withHistoricalDetectionDefaults fills any zero-valued field of cfg with its
+// DefaultHistoricalDetectionConfig counterpart, returning a new config so the
+// caller's original is left untouched. A zero Workers in particular would
+// otherwise start no fetch goroutines, leaving the epoch-feeder goroutine
+// permanently blocked sending to epochsCh and detection silently doing
+// nothing, so every field is defaulted defensively rather than just that one.
+func withHistoricalDetectionDefaults(cfg *HistoricalDetectionConfig) *HistoricalDetectionConfig {
+	def := DefaultHistoricalDetectionConfig()
+	if cfg == nil {
+		return def
+	}
+	out := *cfg
+	if out.Workers == 0 {
+		out.Workers = def.Workers
+	}
+	if out.PrefetchDepth == 0 {
+		out.PrefetchDepth = def.PrefetchDepth
+	}
+	if out.BatchSize == 0 {
+		out.BatchSize = def.BatchSize
+	}
+	return &out
+}
+
+// epochAttestations pairs an epoch with the indexed attestations the beacon
+// node returned for it. The fetch error is carried alongside rather than
+// returned immediately so epochs can still be reassembled in order even when
+// one of them failed to fetch.
+type epochAttestations struct {
+	epoch uint64
+	atts  []*ethpb.IndexedAttestation
+	err   error
+}
+
+// detectHistoricalChainData walks epochs from the latest persisted chain
+// head up to the beacon node's current head. Epochs are fetched concurrently
+// by a bounded worker pool, then fed to slashing detection strictly in epoch
+// order, since the min-max span detector's state is only valid when epochs
+// are processed sequentially. Progress is checkpointed once per
+// cfg.BatchSize processed epochs rather than after every epoch, so a
+// crash never rewinds more than a single in-flight batch.
+func (ds *Service) detectHistoricalChainData(ctx context.Context) {
+	ctx, span := trace.StartSpan(ctx, "detection.detectHistoricalChainData")
+	defer span.End()
+
+	// We fetch both the latest persisted chain head in our DB as well
+	// as the current chain head from the beacon node via gRPC.
+	latestStoredHead, err := ds.slasherDB.ChainHead(ctx)
+	if err != nil {
+		log.WithError(err).Fatal("Could not retrieve chain head from DB")
+	}
+	currentChainHead, err := ds.chainFetcher.ChainHead(ctx)
+	if err != nil {
+		log.WithError(err).Fatal("Cannot retrieve chain head from beacon node")
+	}
+	var startEpoch uint64
+	if latestStoredHead != nil {
+		startEpoch = latestStoredHead.HeadEpoch
+	}
+	endEpoch := currentChainHead.HeadEpoch
+	if startEpoch >= endEpoch {
+		log.Infof("Historical chain data already detected up to epoch %d", startEpoch)
+		return
+	}
+
+	cfg := withHistoricalDetectionDefaults(ds.historicalDetectionCfg)
+
+	epochsCh := make(chan uint64, cfg.PrefetchDepth)
+	resultsCh := make(chan *epochAttestations, cfg.PrefetchDepth)
+
+	var fetchWG sync.WaitGroup
+	for i := uint64(0); i < cfg.Workers; i++ {
+		fetchWG.Add(1)
+		go ds.fetchEpochs(ctx, &fetchWG, epochsCh, resultsCh)
+	}
+	go func() {
+		fetchWG.Wait()
+		close(resultsCh)
+	}()
+	go func() {
+		defer close(epochsCh)
+		for epoch := startEpoch; epoch < endEpoch; epoch++ {
+			select {
+			case epochsCh <- epoch:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	ds.runOrderedDetection(ctx, startEpoch, endEpoch, cfg.BatchSize, resultsCh)
+	log.Infof("Completed slashing detection on historical chain data up to epoch %d", endEpoch)
+}
+
+// fetchEpochs pulls epoch numbers off epochsCh, requests historical
+// attestations for each from the beacon node, and publishes the outcome on
+// resultsCh. Workers may complete epochs out of order; runOrderedDetection
+// is responsible for reassembling them before detection runs.
+func (ds *Service) fetchEpochs(ctx context.Context, wg *sync.WaitGroup, epochsCh <-chan uint64, resultsCh chan<- *epochAttestations) {
+	defer wg.Done()
+	for epoch := range epochsCh {
+		atts, err := ds.historicalFetchFn(ctx, epoch)
+		select {
+		case resultsCh <- &epochAttestations{epoch: epoch, atts: atts, err: err}:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// runOrderedDetection consumes fetch results as they arrive, buffering any
+// that complete out of order, and runs slashing detection strictly in epoch
+// order starting at startEpoch. Once batchSize contiguous epochs have been
+// processed (or the final epoch is reached), the chain head checkpoint is
+// advanced and persisted. An epoch whose fetch failed is logged and skipped
+// without detection, but still counts toward the batch, so the checkpoint
+// can advance past it and it is not fetched again on resumption.
+func (ds *Service) runOrderedDetection(ctx context.Context, startEpoch, endEpoch, batchSize uint64, resultsCh <-chan *epochAttestations) {
+	pending := make(map[uint64]*epochAttestations)
+	nextEpoch := startEpoch
+	processedSinceCheckpoint := uint64(0)
+
+	for nextEpoch < endEpoch {
+		result, ok := pending[nextEpoch]
+		if !ok {
+			res, chanOK := <-resultsCh
+			if !chanOK {
+				return
+			}
+			pending[res.epoch] = res
+			continue
+		}
+		delete(pending, nextEpoch)
+		ds.detectEpoch(ctx, result)
+		nextEpoch++
+		processedSinceCheckpoint++
+
+		if processedSinceCheckpoint >= batchSize || nextEpoch == endEpoch {
+			ds.checkpointChainHead(ctx, nextEpoch-1)
+			processedSinceCheckpoint = 0
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+	}
+}
+
+// detectEpoch runs attester slashing detection across every indexed
+// attestation fetched for a single epoch and submits any slashings found.
+func (ds *Service) detectEpoch(ctx context.Context, result *epochAttestations) {
+	if result.err != nil {
+		log.WithError(result.err).Errorf("Could not fetch attestations for epoch: %d", result.epoch)
+		return
+	}
+	log.Debugf(
+		"Running slashing detection on %d attestations in epoch %d...",
+		len(result.atts),
+		result.epoch,
+	)
+	for _, att := range result.atts {
+		slashings, err := ds.historicalDetectFn(ctx, att)
+		if err != nil {
+			log.WithError(err).Error("Could not detect attester slashings")
+			continue
+		}
+		ds.submitAttesterSlashings(ctx, slashings)
+	}
+	attestationsScannedTotal.Add(float64(len(result.atts)))
+	epochsProcessedTotal.Inc()
+}
+
+// checkpointChainHead persists the chain head as the last processed
+// epoch, matching the original serial implementation's semantics: resuming
+// from this value reprocesses that epoch once more before advancing, which
+// only ever redoes already-safe work rather than skipping anything.
+func (ds *Service) checkpointChainHead(ctx context.Context, lastProcessedEpoch uint64) {
+	if err := ds.slasherDB.SaveChainHead(ctx, &ethpb.ChainHead{HeadEpoch: lastProcessedEpoch}); err != nil {
+		log.WithError(err).Error("Could not persist chain head to disk")
+	}
+}
@@ -0,0 +1,21 @@
+package detection
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	epochsProcessedTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "slasher_historical_epochs_processed_total",
+		Help: "Number of epochs of historical chain data fully processed by the slasher detection service",
+	})
+	attestationsScannedTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "slasher_historical_attestations_scanned_total",
+		Help: "Number of indexed attestations scanned during historical slashing detection",
+	})
+	slashingsDetectedTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "slasher_slashings_detected_total",
+		Help: "Number of slashings found by the slasher detection service and submitted to the beacon node",
+	})
+)
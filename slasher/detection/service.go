@@ -21,47 +21,70 @@ var log = logrus.WithField("prefix", "detection")
 
 // Service struct for the detection service of the slasher.
 type Service struct {
-	ctx                   context.Context
-	cancel                context.CancelFunc
-	slasherDB             db.Database
-	blocksChan            chan *ethpb.SignedBeaconBlock
-	attsChan              chan *ethpb.IndexedAttestation
-	notifier              beaconclient.Notifier
-	chainFetcher          beaconclient.ChainFetcher
-	beaconClient          *beaconclient.Service
-	attesterSlashingsFeed *event.Feed
-	proposerSlashingsFeed *event.Feed
-	minMaxSpanDetector    iface.SpanDetector
-	proposalsDetector     proposerIface.ProposalsDetector
+	ctx                    context.Context
+	cancel                 context.CancelFunc
+	slasherDB              db.Database
+	blocksChan             chan *ethpb.SignedBeaconBlock
+	attsChan               chan *ethpb.IndexedAttestation
+	notifier               beaconclient.Notifier
+	chainFetcher           beaconclient.ChainFetcher
+	beaconClient           *beaconclient.Service
+	minMaxSpanDetector     iface.SpanDetector
+	proposalsDetector      proposerIface.ProposalsDetector
+	historicalDetectionCfg *HistoricalDetectionConfig
+	historicalFetchFn      func(ctx context.Context, epoch uint64) ([]*ethpb.IndexedAttestation, error)
+	historicalDetectFn     func(ctx context.Context, att *ethpb.IndexedAttestation) ([]*ethpb.AttesterSlashing, error)
+	sinks                  []SlashingSink
+	deduper                *slashingDeduper
 }
 
 // Config options for the detection service.
 type Config struct {
-	Notifier              beaconclient.Notifier
-	SlasherDB             db.Database
-	ChainFetcher          beaconclient.ChainFetcher
-	BeaconClient          *beaconclient.Service
-	AttesterSlashingsFeed *event.Feed
-	ProposerSlashingsFeed *event.Feed
+	Notifier                  beaconclient.Notifier
+	SlasherDB                 db.Database
+	ChainFetcher              beaconclient.ChainFetcher
+	BeaconClient              *beaconclient.Service
+	AttesterSlashingsFeed     *event.Feed
+	ProposerSlashingsFeed     *event.Feed
+	HistoricalDetectionConfig *HistoricalDetectionConfig
+	// SlashingSinks are additional destinations, beyond the attester and
+	// proposer slashing feeds, that every detected slashing is routed to
+	// (e.g. a persistent outbox or a monitoring webhook).
+	SlashingSinks []SlashingSink
 }
 
 // NewDetectionService instantiation.
 func NewDetectionService(ctx context.Context, cfg *Config) *Service {
 	ctx, cancel := context.WithCancel(ctx)
-	return &Service{
-		ctx:                   ctx,
-		cancel:                cancel,
-		notifier:              cfg.Notifier,
-		chainFetcher:          cfg.ChainFetcher,
-		slasherDB:             cfg.SlasherDB,
-		beaconClient:          cfg.BeaconClient,
-		blocksChan:            make(chan *ethpb.SignedBeaconBlock, 1),
-		attsChan:              make(chan *ethpb.IndexedAttestation, 1),
-		attesterSlashingsFeed: cfg.AttesterSlashingsFeed,
-		proposerSlashingsFeed: cfg.ProposerSlashingsFeed,
-		minMaxSpanDetector:    attestations.NewSpanDetector(cfg.SlasherDB),
-		proposalsDetector:     proposals.NewProposeDetector(cfg.SlasherDB),
+	historicalDetectionCfg := cfg.HistoricalDetectionConfig
+	if historicalDetectionCfg == nil {
+		historicalDetectionCfg = DefaultHistoricalDetectionConfig()
 	}
+	// The event feeds were the detection service's original, sole delivery
+	// mechanism, so they're always wired in ahead of any additional
+	// caller-supplied sinks rather than delivered to separately: that keeps
+	// FeedSlashingSink as the single code path that sends to them, instead
+	// of risking a caller re-adding it to SlashingSinks and double-delivering
+	// every slashing onto the same feeds.
+	sinks := append([]SlashingSink{NewFeedSlashingSink(cfg.AttesterSlashingsFeed, cfg.ProposerSlashingsFeed)}, cfg.SlashingSinks...)
+	ds := &Service{
+		ctx:                    ctx,
+		cancel:                 cancel,
+		notifier:               cfg.Notifier,
+		chainFetcher:           cfg.ChainFetcher,
+		slasherDB:              cfg.SlasherDB,
+		beaconClient:           cfg.BeaconClient,
+		blocksChan:             make(chan *ethpb.SignedBeaconBlock, 1),
+		attsChan:               make(chan *ethpb.IndexedAttestation, 1),
+		minMaxSpanDetector:     attestations.NewSpanDetector(cfg.SlasherDB),
+		proposalsDetector:      proposals.NewProposeDetector(cfg.SlasherDB),
+		historicalDetectionCfg: historicalDetectionCfg,
+		sinks:                  sinks,
+		deduper:                newSlashingDeduper(cfg.SlasherDB),
+	}
+	ds.historicalFetchFn = ds.beaconClient.RequestHistoricalAttestations
+	ds.historicalDetectFn = ds.DetectAttesterSlashings
+	return ds
 }
 
 // Stop the notifier service.
@@ -86,6 +109,19 @@ func (ds *Service) Start() {
 	<-ch
 	sub.Unsubscribe()
 
+	// Any sink that persists deliveries it has not yet confirmed, such as
+	// the outbox sink, needs a chance to resume them before we start
+	// detecting new slashings.
+	for _, sink := range ds.sinks {
+		replayer, ok := sink.(replayablePendingSink)
+		if !ok {
+			continue
+		}
+		if err := replayer.ReplayPending(ds.ctx); err != nil {
+			log.WithError(err).Error("Could not replay pending slashing sink deliveries")
+		}
+	}
+
 	if !featureconfig.Get().DisableHistoricalDetection {
 		// The detection service runs detection on all historical
 		// chain data since genesis.
@@ -98,55 +134,6 @@ func (ds *Service) Start() {
 	go ds.detectIncomingAttestations(ds.ctx, ds.attsChan)
 }
 
-func (ds *Service) detectHistoricalChainData(ctx context.Context) {
-	ctx, span := trace.StartSpan(ctx, "detection.detectHistoricalChainData")
-	defer span.End()
-	// We fetch both the latest persisted chain head in our DB as well
-	// as the current chain head from the beacon node via gRPC.
-	latestStoredHead, err := ds.slasherDB.ChainHead(ctx)
-	if err != nil {
-		log.WithError(err).Fatal("Could not retrieve chain head from DB")
-	}
-	currentChainHead, err := ds.chainFetcher.ChainHead(ctx)
-	if err != nil {
-		log.WithError(err).Fatal("Cannot retrieve chain head from beacon node")
-	}
-	var latestStoredEpoch uint64
-	if latestStoredHead != nil {
-		latestStoredEpoch = latestStoredHead.HeadEpoch
-	}
-
-	// We retrieve historical chain data from the last persisted chain head in the
-	// slasher DB up to the current beacon node's head epoch we retrieved via gRPC.
-	// If no data was persisted from previous sessions, we request data starting from
-	// the genesis epoch.
-	for epoch := latestStoredEpoch; epoch < currentChainHead.HeadEpoch; epoch++ {
-		indexedAtts, err := ds.beaconClient.RequestHistoricalAttestations(ctx, epoch)
-		if err != nil {
-			log.WithError(err).Errorf("Could not fetch attestations for epoch: %d", epoch)
-		}
-		log.Debugf(
-			"Running slashing detection on %d attestations in epoch %d...",
-			len(indexedAtts),
-			epoch,
-		)
-
-		for _, att := range indexedAtts {
-			slashings, err := ds.DetectAttesterSlashings(ctx, att)
-			if err != nil {
-				log.WithError(err).Error("Could not detect attester slashings")
-				continue
-			}
-			ds.submitAttesterSlashings(ctx, slashings)
-		}
-		latestStoredHead = &ethpb.ChainHead{HeadEpoch: epoch}
-		if err := ds.slasherDB.SaveChainHead(ctx, latestStoredHead); err != nil {
-			log.WithError(err).Error("Could not persist chain head to disk")
-		}
-	}
-	log.Infof("Completed slashing detection on historical chain data up to epoch %d", currentChainHead.HeadEpoch)
-}
-
 func (ds *Service) submitAttesterSlashings(ctx context.Context, slashings []*ethpb.AttesterSlashing) {
 	ctx, span := trace.StartSpan(ctx, "detection.submitAttesterSlashings")
 	defer span.End()
@@ -154,13 +141,21 @@ func (ds *Service) submitAttesterSlashings(ctx context.Context, slashings []*eth
 		slash := slashings[i]
 		if slash != nil && slash.Attestation_1 != nil && slash.Attestation_2 != nil {
 			slashableIndices := sliceutil.IntersectionUint64(slashings[i].Attestation_1.AttestingIndices, slashings[i].Attestation_2.AttestingIndices)
+			if ds.deduper.isDuplicateAttesterSlashing(ctx, slash, slashableIndices) {
+				continue
+			}
 			log.WithFields(logrus.Fields{
 				"sourceEpoch":  slash.Attestation_1.Data.Source.Epoch,
 				"targetEpoch":  slash.Attestation_1.Data.Target.Epoch,
 				"surroundVote": isSurrounding(slash.Attestation_1, slash.Attestation_2),
 				"indices":      slashableIndices,
 			}).Info("Found an attester slashing! Submitting to beacon node")
-			ds.attesterSlashingsFeed.Send(slashings[i])
+			slashingsDetectedTotal.Inc()
+			for _, sink := range ds.sinks {
+				if err := sink.SubmitAttesterSlashing(ctx, slash); err != nil {
+					log.WithError(err).Error("Could not submit attester slashing to sink")
+				}
+			}
 		}
 	}
 }
@@ -169,12 +164,20 @@ func (ds *Service) submitProposerSlashing(ctx context.Context, slashing *ethpb.P
 	ctx, span := trace.StartSpan(ctx, "detection.submitProposerSlashing")
 	defer span.End()
 	if slashing != nil && slashing.Header_1 != nil && slashing.Header_2 != nil {
+		if ds.deduper.isDuplicateProposerSlashing(ctx, slashing) {
+			return
+		}
 		log.WithFields(logrus.Fields{
 			"header1Slot":        slashing.Header_1.Header.Slot,
 			"header2Slot":        slashing.Header_2.Header.Slot,
 			"proposerIdxHeader1": slashing.Header_1.Header.ProposerIndex,
 			"proposerIdxHeader2": slashing.Header_2.Header.ProposerIndex,
 		}).Info("Found a proposer slashing! Submitting to beacon node")
-		ds.proposerSlashingsFeed.Send(slashing)
+		slashingsDetectedTotal.Inc()
+		for _, sink := range ds.sinks {
+			if err := sink.SubmitProposerSlashing(ctx, slashing); err != nil {
+				log.WithError(err).Error("Could not submit proposer slashing to sink")
+			}
+		}
 	}
 }
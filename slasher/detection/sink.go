@@ -0,0 +1,49 @@
+package detection
+
+import (
+	"context"
+
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/shared/event"
+)
+
+// SlashingSink defines a destination detected slashings can be routed to, in
+// addition to the detection service's in-process event feeds. Built-in
+// implementations exist for the event feeds themselves (FeedSlashingSink), a
+// persistent, retrying outbox (OutboxSlashingSink), and an external
+// monitoring webhook (WebhookSlashingSink).
+type SlashingSink interface {
+	SubmitAttesterSlashing(ctx context.Context, slashing *ethpb.AttesterSlashing) error
+	SubmitProposerSlashing(ctx context.Context, slashing *ethpb.ProposerSlashing) error
+}
+
+// FeedSlashingSink routes slashings onto the event feeds consumed by the
+// beacon node subscriber, the detection service's original, sole delivery
+// mechanism. NewDetectionService always includes one of these ahead of any
+// sinks supplied via Config.SlashingSinks, so the feeds are never written to
+// outside this sink; callers assembling a Service's sinks directly, such as
+// in tests, can construct one to preserve that same feed-delivery behavior.
+type FeedSlashingSink struct {
+	attesterSlashingsFeed *event.Feed
+	proposerSlashingsFeed *event.Feed
+}
+
+// NewFeedSlashingSink constructs a FeedSlashingSink over the given feeds.
+func NewFeedSlashingSink(attesterSlashingsFeed, proposerSlashingsFeed *event.Feed) *FeedSlashingSink {
+	return &FeedSlashingSink{
+		attesterSlashingsFeed: attesterSlashingsFeed,
+		proposerSlashingsFeed: proposerSlashingsFeed,
+	}
+}
+
+// SubmitAttesterSlashing sends slash onto the attester slashings feed.
+func (f *FeedSlashingSink) SubmitAttesterSlashing(ctx context.Context, slash *ethpb.AttesterSlashing) error {
+	f.attesterSlashingsFeed.Send(slash)
+	return nil
+}
+
+// SubmitProposerSlashing sends slash onto the proposer slashings feed.
+func (f *FeedSlashingSink) SubmitProposerSlashing(ctx context.Context, slash *ethpb.ProposerSlashing) error {
+	f.proposerSlashingsFeed.Send(slash)
+	return nil
+}
@@ -0,0 +1,172 @@
+package detection
+
+import (
+	"context"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/slasher/db"
+)
+
+// OutboxRetryConfig controls the exponential backoff used when the
+// persistent outbox sink retries delivery after a beacon-node RPC failure.
+type OutboxRetryConfig struct {
+	InitialInterval time.Duration
+	MaxInterval     time.Duration
+	// MaxElapsedTime bounds how long a single delivery attempt is retried
+	// before being abandoned. Zero retries forever; the outbox entry is
+	// still safe on disk and will be retried again on the next
+	// ReplayPending call at startup.
+	MaxElapsedTime time.Duration
+}
+
+// DefaultOutboxRetryConfig returns sane retry defaults for the persistent
+// outbox sink.
+func DefaultOutboxRetryConfig() *OutboxRetryConfig {
+	return &OutboxRetryConfig{
+		InitialInterval: time.Second,
+		MaxInterval:     time.Minute,
+		MaxElapsedTime:  0,
+	}
+}
+
+// OutboxSlashingSink persists every slashing it is asked to submit to
+// slasherDB before attempting delivery through an underlying sink, so a
+// crash between persistence and delivery never loses a detected slashing.
+// Delivery failures are retried in the background with exponential backoff
+// until they succeed, at which point the outbox entry is removed.
+type OutboxSlashingSink struct {
+	ctx       context.Context
+	slasherDB db.Database
+	next      SlashingSink
+	retryCfg  *OutboxRetryConfig
+}
+
+var _ replayablePendingSink = (*OutboxSlashingSink)(nil)
+
+// NewOutboxSlashingSink constructs a persistent outbox that delivers
+// through next, retrying failed deliveries per retryCfg. A nil retryCfg
+// uses DefaultOutboxRetryConfig.
+func NewOutboxSlashingSink(ctx context.Context, slasherDB db.Database, next SlashingSink, retryCfg *OutboxRetryConfig) *OutboxSlashingSink {
+	if retryCfg == nil {
+		retryCfg = DefaultOutboxRetryConfig()
+	}
+	return &OutboxSlashingSink{
+		ctx:       ctx,
+		slasherDB: slasherDB,
+		next:      next,
+		retryCfg:  retryCfg,
+	}
+}
+
+// replayablePendingSink is implemented by sinks that persist deliveries
+// before confirming them and so need a chance to resume any left unfinished
+// by a previous, crashed process. The detection service's Start method
+// type-asserts every configured SlashingSink against this interface.
+type replayablePendingSink interface {
+	ReplayPending(ctx context.Context) error
+}
+
+// replayConcurrency bounds how many outbox entries ReplayPending delivers at
+// once, so a node restarting with a large backlog doesn't spike goroutine
+// and connection usage in proportion to backlog size.
+const replayConcurrency = 16
+
+// ReplayPending resubmits any outbox entries left over from a previous
+// process that crashed before delivery completed. Call this once at startup
+// before the service begins detecting new slashings.
+func (o *OutboxSlashingSink) ReplayPending(ctx context.Context) error {
+	attesterSlashings, err := o.slasherDB.PendingAttesterSlashingOutbox(ctx)
+	if err != nil {
+		return err
+	}
+	proposerSlashings, err := o.slasherDB.PendingProposerSlashingOutbox(ctx)
+	if err != nil {
+		return err
+	}
+	sem := make(chan struct{}, replayConcurrency)
+	for _, slash := range attesterSlashings {
+		slash := slash
+		sem <- struct{}{}
+		go func() {
+			defer func() { <-sem }()
+			o.deliverAttesterSlashing(slash)
+		}()
+	}
+	for _, slash := range proposerSlashings {
+		slash := slash
+		sem <- struct{}{}
+		go func() {
+			defer func() { <-sem }()
+			o.deliverProposerSlashing(slash)
+		}()
+	}
+	return nil
+}
+
+// SubmitAttesterSlashing persists slash to the outbox, deduplicating
+// against any identical entry still pending, then attempts delivery in the
+// background. Entries are removed once delivered, so an identical slashing
+// submitted after that is delivered again.
+func (o *OutboxSlashingSink) SubmitAttesterSlashing(ctx context.Context, slash *ethpb.AttesterSlashing) error {
+	isNew, err := o.slasherDB.SaveAttesterSlashingOutboxEntry(ctx, slash)
+	if err != nil {
+		return err
+	}
+	if !isNew {
+		return nil
+	}
+	go o.deliverAttesterSlashing(slash)
+	return nil
+}
+
+// SubmitProposerSlashing persists slash to the outbox, deduplicating against
+// any identical entry still pending, then attempts delivery in the
+// background. Entries are removed once delivered, so an identical slashing
+// submitted after that is delivered again.
+func (o *OutboxSlashingSink) SubmitProposerSlashing(ctx context.Context, slash *ethpb.ProposerSlashing) error {
+	isNew, err := o.slasherDB.SaveProposerSlashingOutboxEntry(ctx, slash)
+	if err != nil {
+		return err
+	}
+	if !isNew {
+		return nil
+	}
+	go o.deliverProposerSlashing(slash)
+	return nil
+}
+
+func (o *OutboxSlashingSink) deliverAttesterSlashing(slash *ethpb.AttesterSlashing) {
+	op := func() error {
+		return o.next.SubmitAttesterSlashing(o.ctx, slash)
+	}
+	if err := backoff.Retry(op, o.newBackoff()); err != nil {
+		log.WithError(err).Error("Giving up on delivering attester slashing through outbox sink")
+		return
+	}
+	if err := o.slasherDB.DeleteAttesterSlashingOutboxEntry(o.ctx, slash); err != nil {
+		log.WithError(err).Error("Could not remove delivered attester slashing from outbox")
+	}
+}
+
+func (o *OutboxSlashingSink) deliverProposerSlashing(slash *ethpb.ProposerSlashing) {
+	op := func() error {
+		return o.next.SubmitProposerSlashing(o.ctx, slash)
+	}
+	if err := backoff.Retry(op, o.newBackoff()); err != nil {
+		log.WithError(err).Error("Giving up on delivering proposer slashing through outbox sink")
+		return
+	}
+	if err := o.slasherDB.DeleteProposerSlashingOutboxEntry(o.ctx, slash); err != nil {
+		log.WithError(err).Error("Could not remove delivered proposer slashing from outbox")
+	}
+}
+
+func (o *OutboxSlashingSink) newBackoff() backoff.BackOff {
+	b := backoff.NewExponentialBackOff()
+	b.InitialInterval = o.retryCfg.InitialInterval
+	b.MaxInterval = o.retryCfg.MaxInterval
+	b.MaxElapsedTime = o.retryCfg.MaxElapsedTime
+	return backoff.WithContext(b, o.ctx)
+}
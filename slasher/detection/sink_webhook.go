@@ -0,0 +1,72 @@
+package detection
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+)
+
+// WebhookSlashingSink POSTs a JSON payload for every detected slashing to a
+// configured URL, for routing to external monitoring systems.
+type WebhookSlashingSink struct {
+	url        string
+	httpClient *http.Client
+}
+
+// NewWebhookSlashingSink constructs a webhook sink that posts to url.
+func NewWebhookSlashingSink(url string) *WebhookSlashingSink {
+	return &WebhookSlashingSink{
+		url:        url,
+		httpClient: &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+type attesterSlashingWebhookPayload struct {
+	Type     string                  `json:"type"`
+	Slashing *ethpb.AttesterSlashing `json:"attester_slashing"`
+}
+
+type proposerSlashingWebhookPayload struct {
+	Type     string                  `json:"type"`
+	Slashing *ethpb.ProposerSlashing `json:"proposer_slashing"`
+}
+
+// SubmitAttesterSlashing posts slash as JSON to the configured webhook URL.
+func (w *WebhookSlashingSink) SubmitAttesterSlashing(ctx context.Context, slash *ethpb.AttesterSlashing) error {
+	return w.post(ctx, &attesterSlashingWebhookPayload{Type: "attester_slashing", Slashing: slash})
+}
+
+// SubmitProposerSlashing posts slash as JSON to the configured webhook URL.
+func (w *WebhookSlashingSink) SubmitProposerSlashing(ctx context.Context, slash *ethpb.ProposerSlashing) error {
+	return w.post(ctx, &proposerSlashingWebhookPayload{Type: "proposer_slashing", Slashing: slash})
+}
+
+func (w *WebhookSlashingSink) post(ctx context.Context, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := w.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := resp.Body.Close(); cerr != nil {
+			log.WithError(cerr).Error("Could not close webhook response body")
+		}
+	}()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned non-2xx status code: %d", resp.StatusCode)
+	}
+	return nil
+}